@@ -16,14 +16,15 @@
 // To New, you pass a function that defines the coroutine's execution much like
 // you pass a function to the 'go' statement that defines the goroutine's
 // execution. The difference is that the coroutine doesn't start right away;
-// instead, another goroutine must call the Resume function returned by New.
+// instead, another goroutine must call the Resume method of the Coroutine
+// returned by New.
 //
-// The Resume function blocks the calling goroutine while the coroutine is
+// The Resume method blocks the calling goroutine while the coroutine is
 // executing. The coroutine may then call the 'yield' function which is passed
-// to its defining function. 'yield', in turn, blocks the coroutine until the
-// Resume function is called again.
+// to its defining function. 'yield', in turn, blocks the coroutine until
+// Resume is called again.
 //
-// Thus, while a goroutine is blocked on calling a Resume func, the coroutine is
+// Thus, while a goroutine is blocked on calling Resume, the coroutine is
 // executing; and, while the coroutine is blocked on calling its 'yield' func,
 // the other goroutine is executing.
 //
@@ -33,6 +34,13 @@
 // Since the participating goroutine's executions never overlap and have a
 // well-defined order, they are synchronized.
 //
+// Inspecting a coroutine's state
+//
+// The Coroutine returned by New and NewCoroutine also exposes Status, which
+// reports whether the coroutine is StatusSuspended, StatusRunning or
+// StatusDead without resuming it, and Cause, which reports why a dead
+// coroutine ended.
+//
 // Killing and cancelling coroutines
 //
 // To help prevent goroutine leaks, when a coroutine is blocked on a 'yield' and
@@ -43,16 +51,61 @@
 // the KillOnContextDone option. When the context is cancelled or reaches its
 // deadline, the coroutine is killed.
 //
+// A coroutine can also be killed explicitly with its Kill method, which takes
+// a cause that'll be wrapped in the ErrKilled the coroutine's 'yield' panics
+// with. If the coroutine is currently running, the kill is deferred until it
+// next yields.
+//
 // This kind of panic is recovered by the library. The coroutine's function may
 // intercept such panics in its own deferred recovery code.
 //
-// The killed coroutine's Resume func, if ever called, will return false, as if
-// the coroutine had exited normally.
+// The killed coroutine's Resume method, if ever called, will return false, as
+// if the coroutine had exited normally.
+//
+// Yielding from deferred code
+//
+// A coroutine's run function may defer a call that transitively calls
+// 'yield' after its normal return path, e.g. "defer yield()". If nothing is
+// left resuming the coroutine by the time such a call runs -- e.g. it was
+// abandoned, or is only reachable through leak detection -- it's treated as
+// a no-op, or panics with ErrYieldAfterReturn if PanicOnYieldAfterReturn was
+// set.
+//
+// If a Resume loop is still actively driving the coroutine, though, such a
+// call can't be told apart from an ordinary yield: Go runs a function's own
+// deferred calls as part of that same function returning, before any code
+// of ours gets a chance to notice run is done. In that case the deferred
+// yield behaves like one last real yield instead -- Resume returns true for
+// it, and the coroutine only reports itself dead on the following Resume.
 //
 // Behavior on panics
 //
-// If the coroutine's goroutine panics, its Resume func returns false, as if the
-// coroutine had exited normally.
+// By default, if the coroutine's goroutine panics with anything other than
+// an ErrKilled, its Resume method returns false, as if the coroutine had
+// exited normally, and the panic is re-raised on the coroutine's own
+// goroutine, which crashes the program same as any other unrecovered
+// panic would.
+//
+// Passing WithPanicPropagation instead marshals the panic back to whichever
+// goroutine calls Resume: it's captured along with its stack trace, and
+// Resume re-panics with a CoroutinePanic on the caller's stack, so it can be
+// recovered and inspected like any other error. Cause also reports the
+// CoroutinePanic for a coroutine that panicked this way.
+//
+// Symmetric coroutines
+//
+// New and NewCoroutine are asymmetric: a coroutine only ever hands control
+// back to whichever goroutine last resumed it. NewSymmetric instead creates
+// coroutines that are first-class values which can transfer control
+// directly to one another, without an intermediate goroutine scheduling the
+// handoff -- useful for producer/consumer pipelines and cooperative
+// schedulers built out of several coroutines.
+//
+// The Coroutine returned by NewSymmetric is still started and inspected
+// with Resume, Status, Cause and Kill, which behave as documented above,
+// except Resume only returns once the coroutine has returned, panicked or
+// been killed, since by the time any single transfer happens control may be
+// running inside an arbitrary peer. See NewSymmetric.
 package coro
 
 import (
@@ -60,6 +113,9 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 )
 
 // Resume is an alias for a function that yields control to a coroutine,
@@ -69,8 +125,10 @@ type Resume = func() (alive bool)
 // Options is an internal configuration type. It's configured via SetOptions
 // provided when creating a coroutine with New.
 type Options struct {
-	g       GoFunc
-	killCtx context.Context
+	g                       GoFunc
+	killCtx                 context.Context
+	propagatePanics         bool
+	panicOnYieldAfterReturn bool
 }
 
 // A SetOption sets an option on the
@@ -84,6 +142,30 @@ func KillOnContextDone(ctx context.Context) SetOption {
 	}
 }
 
+// WithPanicPropagation makes a coroutine marshal panics other than ErrKilled
+// back to the goroutine that calls Resume, instead of crashing the program
+// on the coroutine's own goroutine. See the package-level documentation on
+// "Behavior on panics".
+func WithPanicPropagation() SetOption {
+	return func(o *Options) {
+		o.propagatePanics = true
+	}
+}
+
+// PanicOnYieldAfterReturn makes 'yield' panic with an ErrYieldAfterReturn
+// when it's called after the coroutine's run function has already returned
+// -- which can happen if run defers a call that transitively yields -- and
+// nothing is left resuming the coroutine to receive it. By default, such a
+// call is instead a no-op that returns immediately, since by that point
+// there's no resuming goroutine left to synchronize with. See "Yielding
+// from deferred code" in the package documentation for the case where a
+// live Resume loop is still driving the coroutine when this happens.
+func PanicOnYieldAfterReturn() SetOption {
+	return func(o *Options) {
+		o.panicOnYieldAfterReturn = true
+	}
+}
+
 // A GoFunc spawns goroutines.
 type GoFunc func(func())
 
@@ -99,6 +181,127 @@ var defaultOptions = []SetOption{
 	WithGoFunc(func(f func()) { go f() }),
 }
 
+// A Status reports a Coroutine's lifecycle state, as returned by its Status
+// method.
+type Status int32
+
+const (
+	// StatusSuspended means the coroutine hasn't started yet, or is blocked
+	// on a yield, waiting to be resumed.
+	StatusSuspended Status = iota
+	// StatusRunning means the coroutine is currently executing. Since a
+	// coroutine only ever runs while some other goroutine is blocked on
+	// Resume, a goroutine can only observe this on a Coroutine other than
+	// the one it's currently resuming.
+	StatusRunning
+	// StatusDead means the coroutine has returned, panicked or been killed,
+	// and calling Resume on it will return false without running it again.
+	StatusDead
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusSuspended:
+		return "suspended"
+	case StatusRunning:
+		return "running"
+	case StatusDead:
+		return "dead"
+	default:
+		return fmt.Sprintf("coro.Status(%d)", int32(s))
+	}
+}
+
+// A Coroutine is a handle to a coroutine created by New or NewCoroutine. It
+// lets callers resume the coroutine, inspect its lifecycle state without
+// resuming it, and kill it.
+type Coroutine struct {
+	resume Resume
+	state  *coroState
+}
+
+// coroState holds a coroutine's shared lifecycle state. It's kept separate
+// from Coroutine itself because the coroutine's own background goroutine
+// needs to keep updating it for as long as that goroutine is parked
+// waiting to be resumed -- but must never hold a reference to the
+// Coroutine handle while doing so, or the handle (and the finalizer armed
+// on it) would stay reachable for as long as the goroutine is alive,
+// defeating the leak detection the finalizer exists to provide. See
+// NewCoroutine and NewSymmetric.
+type coroState struct {
+	status int32 // atomic Status
+
+	mu         sync.Mutex
+	cause      error
+	killCause  error
+	cancelKill context.CancelFunc
+
+	// wake and done are only set for a coroutine created by NewSymmetric;
+	// see transfer there.
+	wake chan struct{}
+	done chan struct{}
+}
+
+// Resume yields control to the coroutine, blocking until the coroutine
+// either yields control back or returns.
+func (c *Coroutine) Resume() (alive bool) {
+	return c.resume()
+}
+
+// Status reports the coroutine's current lifecycle state without resuming
+// it.
+func (c *Coroutine) Status() Status {
+	return Status(atomic.LoadInt32(&c.state.status))
+}
+
+// Cause reports why a dead coroutine ended: nil for a normal return, or an
+// ErrKilled wrapping an ErrLeak, a context error or the cause passed to
+// Kill. Cause returns nil until the coroutine is dead.
+func (c *Coroutine) Cause() error {
+	return c.state.loadCause()
+}
+
+// Kill terminates a suspended coroutine, causing its next (or current, if
+// it's blocked waiting to be resumed) attempt to suspend -- a call to
+// 'yield', or a transfer into it for a coroutine created with NewSymmetric
+// -- to panic with an ErrKilled wrapping cause. If the coroutine is
+// currently running, the kill is deferred until it next suspends. Kill on
+// an already-dead coroutine is a no-op that returns its existing Cause.
+func (c *Coroutine) Kill(cause error) error {
+	if c.Status() == StatusDead {
+		return c.Cause()
+	}
+	if cause == nil {
+		cause = errors.New("coro: killed")
+	}
+	c.state.mu.Lock()
+	c.state.killCause = cause
+	c.state.mu.Unlock()
+	c.state.cancelKill()
+	return nil
+}
+
+func (s *coroState) setCause(err error) {
+	s.mu.Lock()
+	s.cause = err
+	s.mu.Unlock()
+}
+
+func (s *coroState) killCauseOr(fallback error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.killCause != nil {
+		return s.killCause
+	}
+	return fallback
+}
+
+func (s *coroState) loadCause() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cause
+}
+
 // New creates a coroutine, a function running in a new goroutine that is
 // explicitly suspended and resumed.
 //
@@ -106,64 +309,114 @@ var defaultOptions = []SetOption{
 // the next time it is suspended. See KillOnContextDone.
 //
 // See package-level documentation for details.
-func New(ctx context.Context, run func(yield func()), setOptions ...SetOption) Resume {
+func New(ctx context.Context, run func(yield func()), setOptions ...SetOption) *Coroutine {
 	return NewCoroutine(run, append(setOptions, KillOnContextDone(ctx))...)
 }
 
 // NewCoroutine is like New, except it doesn't take a context. (A context can
 // still be used for cancelling with KillOnContextDone).
-func NewCoroutine(run func(yield func()), setOptions ...SetOption) Resume {
+func NewCoroutine(run func(yield func()), setOptions ...SetOption) *Coroutine {
 	var options Options
 	for _, setOption := range append(defaultOptions, setOptions...) {
 		setOption(&options)
 	}
 
+	killCtx, cancelKill := context.WithCancel(options.killCtx)
+
 	yieldCh := make(chan struct{})
 	garbageCollected := make(chan struct{})
 
-	var resumeToken bool
-	resume := func() bool {
-		resumeToken = !resumeToken
+	state := &coroState{cancelKill: cancelKill}
+	atomic.StoreInt32(&state.status, int32(StatusSuspended))
+	co := &Coroutine{state: state}
+
+	repanicPropagated := func() {
+		if cp, ok := state.loadCause().(CoroutinePanic); ok {
+			panic(cp)
+		}
+	}
 
+	resume := func() bool {
 		// resume...
 		_, ok := <-yieldCh
 		if !ok {
 			// resumed dead coroutine
+			repanicPropagated()
 			return false
 		}
 
 		// ... and wait for yield or return
 		_, ok = <-yieldCh
+		if !ok {
+			repanicPropagated()
+		}
 		return ok
 	}
+	co.resume = resume
 
-	runtime.SetFinalizer(&resumeToken, func(interface{}) {
+	// The finalizer is armed on co itself, the handle returned to the
+	// caller, rather than on anything the background goroutine below
+	// references: that goroutine runs for as long as the coroutine is
+	// suspended waiting to be resumed, so if it held a reference to co, co
+	// would never become unreachable -- and this finalizer would never
+	// fire -- for as long as the coroutine is leaked, which defeats the
+	// very detection it exists to provide.
+	runtime.SetFinalizer(co, func(*Coroutine) {
 		close(garbageCollected)
 	})
 
 	var yieldPanic error
+	var returned int32 // atomic; set once run has returned, before yieldCh is closed
 
-	waitResume := func() {
+	// trySend announces a yield (or the initial handoff into run) on
+	// yieldCh. Like waitResume, it's a select so that a yield nobody will
+	// ever resume -- because it's been garbage collected, or killed -- is
+	// caught here too, instead of blocking forever.
+	//
+	// It stores StatusSuspended before attempting the send, rather than
+	// leaving that to its caller, because the send is what unblocks a
+	// concurrent Resume: by the time Resume's second receive returns, the
+	// status must already be visible as suspended.
+	trySend := func() {
+		atomic.StoreInt32(&state.status, int32(StatusSuspended))
 		select {
 		case yieldCh <- struct{}{}:
 			return
 		case <-garbageCollected:
 			yieldPanic = ErrKilled{ErrLeak}
-		case <-options.killCtx.Done():
-			yieldPanic = ErrKilled{options.killCtx.Err()}
+		case <-killCtx.Done():
+			yieldPanic = ErrKilled{state.killCauseOr(killCtx.Err())}
 		}
 		panic(yieldPanic)
 	}
 
+	waitResume := func() {
+		trySend()
+		atomic.StoreInt32(&state.status, int32(StatusRunning))
+	}
+
 	options.g(func() {
 		defer close(yieldCh)
 
 		defer func() {
+			defer atomic.StoreInt32(&state.status, int32(StatusDead))
+
+			// run has returned or panicked out: yieldCh is about to be
+			// closed by the defer above, so any later call to 'yield' (e.g.
+			// from a defer in run that transitively yields) must not try to
+			// use it.
+			atomic.StoreInt32(&returned, 1)
+
 			r := recover()
 			if r == nil {
 				return
 			}
 			if err, ok := r.(error); ok && errors.As(err, &ErrKilled{}) {
+				state.setCause(err)
+				return
+			}
+			if options.propagatePanics {
+				state.setCause(CoroutinePanic{Value: r, Stack: debug.Stack()})
 				return
 			}
 			panic(r)
@@ -176,23 +429,55 @@ func NewCoroutine(run func(yield func()), setOptions ...SetOption) Resume {
 				panic(yieldPanic)
 			}
 
-			// make call to Resume return
-			yieldCh <- struct{}{}
+			if atomic.LoadInt32(&returned) != 0 {
+				// run has already returned; this call can only come from a
+				// deferred function that transitively yields. There's no
+				// resuming goroutine left to hand control to, so treat it
+				// as a no-op rather than send on the about-to-be-closed
+				// yieldCh.
+				//
+				// This only catches a yield made after run has fully
+				// unwound, e.g. from the captured closure itself, or while
+				// nothing is left to ever call Resume again (see
+				// TestLeakDeferredYield). It can't catch a "defer yield()"
+				// registered in run's own body while a Resume loop is still
+				// driving the coroutine: Go runs a function's own deferred
+				// calls as part of that function returning, which happens
+				// before 'returned' is set below -- there's no hook in
+				// between "run's body finished" and "run's own defers ran"
+				// for us to set it any earlier. Such a call goes through
+				// the normal trySend/waitResume path instead, behaving like
+				// one last real yield: Resume returns true for it, and only
+				// the following Resume reports the coroutine dead. See
+				// "Yielding from deferred code" in the package docs.
+				if options.panicOnYieldAfterReturn {
+					panic(ErrYieldAfterReturn)
+				}
+				return
+			}
+
+			// announce the yield, making the call to Resume return
+			trySend()
 
 			waitResume()
 		})
 	})
 
-	return resume
+	return co
 }
 
 // ErrLeak is the error with which a coroutine is killed when it's
 // detected to be stuck forever.
 //
-// Currently, this means that the coroutine's associated Resume function has
-// been garbage-collected.
+// Currently, this means that the coroutine's associated Coroutine has been
+// garbage-collected.
 var ErrLeak = errors.New("coro: coroutine leaked")
 
+// ErrYieldAfterReturn is the error 'yield' panics with when it's called
+// after the coroutine's run function has already returned, and
+// PanicOnYieldAfterReturn was set.
+var ErrYieldAfterReturn = errors.New("coro: yield called after run returned")
+
 // An ErrKilled is the error with which the library kills a goroutine.
 //
 // See package-level documentation for details.
@@ -208,6 +493,19 @@ func (err ErrKilled) Unwrap() error {
 	return err.By
 }
 
+// A CoroutinePanic is the error a Coroutine's Resume re-panics with when its
+// coroutine panicked and WithPanicPropagation was set. Value is whatever the
+// coroutine's goroutine panicked with, and Stack is its stack trace,
+// captured with debug.Stack at the point of the panic.
+type CoroutinePanic struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (p CoroutinePanic) Error() string {
+	return fmt.Sprintf("coro: coroutine panicked: %v\n%s", p.Value, p.Stack)
+}
+
 // Generate runs a generator function in a coroutine.
 //
 // The generator starts running when the returned "next" function is called.
@@ -216,7 +514,7 @@ func (err ErrKilled) Unwrap() error {
 // is blocked until the generator either yields or returns a value.
 //
 // Yielded values are set to the variable pointed by the argument of type
-// *Yielded on 
+// *Yielded on
 //
 // If your generator doesn't yield useful values, consider the simpler Loop
 // instead.
@@ -233,8 +531,8 @@ func Generate[Returned, Yielded any](
 	setOption ...SetOption,
 ) (next func(*Returned, *Yielded) (alive bool)) {
 	var yp *Yielded
-	var rp *Returned 
-	resume := New(ctx, func(yield func()) {
+	var rp *Returned
+	co := New(ctx, func(yield func()) {
 		*rp = run(func(v Yielded) {
 			*yp = v
 			yield()
@@ -242,8 +540,7 @@ func Generate[Returned, Yielded any](
 	}, setOption...)
 	return func(r *Returned, y *Yielded) bool {
 		yp, rp = y, r
-		alive := resume()
-		return alive
+		return co.Resume()
 	}
 }
 
@@ -253,16 +550,15 @@ func Loop[Returned any](
 	run func(yield func()) Returned,
 	setOption ...SetOption,
 ) (next func(*Returned) (alive bool)) {
-	var rp *Returned 
-	resume := New(ctx, func(yield func()) {
+	var rp *Returned
+	co := New(ctx, func(yield func()) {
 		*rp = run(func() {
 			yield()
 		})
 	}, setOption...)
 	return func(r *Returned) bool {
 		rp = r
-		alive := resume()
-		return alive
+		return co.Resume()
 	}
 }
 
@@ -273,7 +569,7 @@ func Enumerate[Yielded any](
 	setOption ...SetOption,
 ) (next func(*Yielded) (alive bool)) {
 	var yp *Yielded
-	resume := New(ctx, func(yield func()) {
+	co := New(ctx, func(yield func()) {
 		run(func(v Yielded) {
 			*yp = v
 			yield()
@@ -281,7 +577,177 @@ func Enumerate[Yielded any](
 	}, setOption...)
 	return func(y *Yielded) bool {
 		yp = y
-		alive := resume()
-		return alive
+		return co.Resume()
+	}
+}
+
+// Channel runs a function in a coroutine that, unlike Generate, exchanges
+// values in both directions: each call to the returned "next" function
+// passes a value into the coroutine, and each call to "yield" inside run
+// passes a value back out.
+//
+// The first call to "next" delivers its In value as run's "first" argument.
+// Every subsequent call to "next" delivers its In value as the return value
+// of the "yield" call that's currently blocking the coroutine.
+//
+// Yielded values are set to the variable pointed by the argument of type
+// *Out, and the value run returns is set to the variable pointed by the
+// argument of type *Ret, same as with Generate.
+//
+// See ExampleChannel.
+func Channel[In, Out, Ret any](
+	ctx context.Context,
+	run func(first In, yield func(Out) In) Ret,
+	setOption ...SetOption,
+) (next func(in In, out *Out, ret *Ret) (alive bool)) {
+	var in In
+	var outp *Out
+	var retp *Ret
+	co := New(ctx, func(innerYield func()) {
+		*retp = run(in, func(out Out) In {
+			*outp = out
+			innerYield()
+			return in
+		})
+	}, setOption...)
+	return func(i In, o *Out, r *Ret) bool {
+		in, outp, retp = i, o, r
+		return co.Resume()
+	}
+}
+
+// NewSymmetric creates a symmetric coroutine: unlike the coroutines created
+// by New and NewCoroutine, whose run function only ever hands control back
+// to whichever goroutine last resumed it, a symmetric coroutine's run
+// function can transfer control directly to any other symmetric Coroutine,
+// without an intermediate goroutine scheduling the handoff.
+//
+// run receives self, the Coroutine being created, and transfer. Calling
+// transfer(target) hands control to target, blocking the calling coroutine
+// until control is transferred back to it -- by target, or by anything
+// else reachable through further transfers.
+//
+// The returned Coroutine is started with Resume, same as one created by
+// New, except Resume blocks until the coroutine returns, panics or is
+// killed, rather than until its next transfer -- by then, control may be
+// running inside an arbitrary peer, with no notion of "back to the caller"
+// left to report.
+//
+// Transferring to a dead or killed Coroutine makes transfer panic with an
+// ErrKilled wrapping the target's Cause, on the transferring coroutine:
+// unlike Resume, there's no caller left to report that to by returning
+// false.
+//
+// See the package-level documentation for how killing and panics are
+// handled, which applies to symmetric coroutines the same way.
+func NewSymmetric(ctx context.Context, run func(self *Coroutine, transfer func(target *Coroutine)), setOptions ...SetOption) *Coroutine {
+	var options Options
+	for _, setOption := range append(defaultOptions, append(setOptions, KillOnContextDone(ctx))...) {
+		setOption(&options)
+	}
+
+	killCtx, cancelKill := context.WithCancel(options.killCtx)
+
+	wake := make(chan struct{})
+	done := make(chan struct{})
+	garbageCollected := make(chan struct{})
+
+	state := &coroState{cancelKill: cancelKill, wake: wake, done: done}
+	atomic.StoreInt32(&state.status, int32(StatusSuspended))
+	co := &Coroutine{state: state}
+
+	repanicPropagated := func() {
+		if cp, ok := state.loadCause().(CoroutinePanic); ok {
+			panic(cp)
+		}
+	}
+
+	// waitWake blocks co until somebody transfers control to it -- the
+	// symmetric equivalent of waitResume, except the handoff is a transfer
+	// (or the initial Resume) rather than a plain Resume.
+	waitWake := func() {
+		atomic.StoreInt32(&state.status, int32(StatusSuspended))
+		select {
+		case <-wake:
+		case <-garbageCollected:
+			panic(ErrKilled{ErrLeak})
+		case <-killCtx.Done():
+			panic(ErrKilled{state.killCauseOr(killCtx.Err())})
+		}
+		atomic.StoreInt32(&state.status, int32(StatusRunning))
+	}
+
+	transfer := func(target *Coroutine) {
+		if target.Status() == StatusDead {
+			panic(ErrKilled{target.Cause()})
+		}
+		select {
+		case target.state.wake <- struct{}{}:
+		case <-target.state.done:
+			// target died concurrently, between the Status check above and
+			// this select: its own waitWake has already exited through one
+			// of its own garbageCollected/killCtx.Done cases, so nobody is
+			// left to receive on target.state.wake, and we'd otherwise
+			// block here forever.
+			panic(ErrKilled{target.Cause()})
+		case <-garbageCollected:
+			panic(ErrKilled{ErrLeak})
+		case <-killCtx.Done():
+			panic(ErrKilled{state.killCauseOr(killCtx.Err())})
+		}
+		waitWake()
+	}
+
+	// The finalizer is armed on co, not on anything the background
+	// goroutine below references, for the same reason as in NewCoroutine:
+	// otherwise co would stay reachable, and this finalizer would never
+	// fire, for as long as the goroutine is parked waiting for a transfer
+	// that never comes.
+	runtime.SetFinalizer(co, func(*Coroutine) {
+		close(garbageCollected)
+	})
+
+	options.g(func() {
+		defer close(done)
+
+		defer func() {
+			defer atomic.StoreInt32(&state.status, int32(StatusDead))
+
+			r := recover()
+			if r == nil {
+				return
+			}
+			if err, ok := r.(error); ok && errors.As(err, &ErrKilled{}) {
+				state.setCause(err)
+				return
+			}
+			if options.propagatePanics {
+				state.setCause(CoroutinePanic{Value: r, Stack: debug.Stack()})
+				return
+			}
+			panic(r)
+		}()
+
+		waitWake()
+		run(co, transfer)
+	})
+
+	// Resume kicks the coroutine off by transferring control into it, and
+	// blocks until it's done: see the doc comment above for why, unlike an
+	// asymmetric coroutine's Resume, this can't return early when the
+	// coroutine merely transfers control elsewhere.
+	co.resume = func() bool {
+		if Status(atomic.LoadInt32(&state.status)) != StatusDead {
+			select {
+			case wake <- struct{}{}:
+			case <-done:
+			}
+		}
+
+		<-done
+		repanicPropagated()
+		return false
 	}
+
+	return co
 }
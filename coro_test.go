@@ -14,7 +14,7 @@ import (
 var ctx = context.Background()
 
 func Example() {
-	resume := coro.New(ctx, func(yield func()) {
+	co := coro.New(ctx, func(yield func()) {
 		for i := 1; i <= 3; i++ {
 			fmt.Println("coroutine:", i)
 			yield()
@@ -23,7 +23,7 @@ func Example() {
 	})
 
 	fmt.Println("not started yet")
-	for resume() {
+	for co.Resume() {
 		fmt.Println("yielded")
 	}
 	fmt.Println("returned")
@@ -62,11 +62,44 @@ func ExampleGenerate() {
 	// returned: done
 }
 
+func ExampleChannel() {
+	var out int
+	var ret error
+	next := coro.Channel(ctx, func(first int, yield func(int) int) error {
+		in := first
+		for in != 0 {
+			fmt.Println("coroutine got:", in)
+			in = yield(in * 2)
+		}
+		return errors.New("done")
+	})
+
+	for in := 1; next(in, &out, &ret); {
+		fmt.Println("caller got:", out)
+		in = out
+		if out > 8 {
+			in = 0
+		}
+	}
+	fmt.Println("returned:", ret)
+
+	// Output:
+	// coroutine got: 1
+	// caller got: 2
+	// coroutine got: 2
+	// caller got: 4
+	// coroutine got: 4
+	// caller got: 8
+	// coroutine got: 8
+	// caller got: 16
+	// returned: done
+}
+
 func TestLeak(t *testing.T) {
 	panicked := make(chan interface{})
 
 	func() {
-		resume := coro.NewCoroutine(func(yield func()) {
+		co := coro.NewCoroutine(func(yield func()) {
 			defer func() {
 				if r := recover(); r != nil {
 					panicked <- r
@@ -75,7 +108,7 @@ func TestLeak(t *testing.T) {
 			}()
 			yield()
 		})
-		resume()
+		co.Resume()
 	}()
 
 	for {
@@ -91,12 +124,107 @@ func TestLeak(t *testing.T) {
 	}
 }
 
+// TestLeakDeferredYield mirrors TestLeak, except the coroutine yields from a
+// deferred call after its normal return path, which must not deadlock or
+// crash the program with a send on the (by-then-closing) yield channel.
+func TestLeakDeferredYield(t *testing.T) {
+	panicked := make(chan interface{})
+
+	func() {
+		co := coro.NewCoroutine(func(yield func()) {
+			defer func() {
+				if r := recover(); r != nil {
+					panicked <- r
+					panic(r)
+				}
+			}()
+			defer yield()
+		})
+		co.Resume()
+	}()
+
+	for {
+		runtime.GC()
+		select {
+		case p := <-panicked:
+			if err, ok := p.(error); !ok || !errors.As(err, &coro.ErrKilled{}) || !errors.Is(err, coro.ErrLeak) {
+				t.Errorf("expected ErrLeak within an ErrKilled, got %v", p)
+			}
+			return
+		default:
+		}
+	}
+}
+
+// TestDeferredYieldDuringLiveResumeLoop is like TestLeakDeferredYield, except
+// the coroutine is driven by the idiomatic for co.Resume() {} loop instead of
+// being abandoned: the deferred yield fires while a real resumer is still
+// around, rather than only being caught by leak detection.
+//
+// As documented under "Yielding from deferred code", a "defer yield()" can't
+// be told apart from an ordinary yield while something is still actively
+// resuming the coroutine, so it behaves like one last real yield: Resume
+// returns true once for it, and only the following Resume reports the
+// coroutine dead. This test pins down that behavior; it must still
+// terminate safely either way, as if run had returned normally.
+func TestDeferredYieldDuringLiveResumeLoop(t *testing.T) {
+	co := coro.NewCoroutine(func(yield func()) {
+		defer yield()
+	})
+
+	resumes := 0
+	for co.Resume() {
+		resumes++
+	}
+
+	if resumes != 1 {
+		t.Fatalf("expected exactly one extra Resume for the deferred yield, got %d", resumes)
+	}
+	if got := co.Status(); got != coro.StatusDead {
+		t.Fatalf("expected coroutine to be dead, got %v", got)
+	}
+	if err := co.Cause(); err != nil {
+		t.Fatalf("expected a normal return to have a nil Cause, got %v", err)
+	}
+}
+
+func TestYieldAfterReturn(t *testing.T) {
+	var yield func()
+	co := coro.NewCoroutine(func(y func()) {
+		yield = y
+	})
+
+	for co.Resume() {
+	}
+
+	// Calling yield once run has returned should be a harmless no-op.
+	yield()
+}
+
+func TestPanicOnYieldAfterReturn(t *testing.T) {
+	var yield func()
+	co := coro.NewCoroutine(func(y func()) {
+		yield = y
+	}, coro.PanicOnYieldAfterReturn())
+
+	for co.Resume() {
+	}
+
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); !ok || !errors.Is(err, coro.ErrYieldAfterReturn) {
+			t.Fatalf("expected ErrYieldAfterReturn, got %v", r)
+		}
+	}()
+	yield()
+}
+
 func TestKillOnContextDone(t *testing.T) {
 	panicked := make(chan interface{}, 1)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	resume := coro.NewCoroutine(func(yield func()) {
+	co := coro.NewCoroutine(func(yield func()) {
 		defer func() {
 			if r := recover(); r != nil {
 				panicked <- r
@@ -109,7 +237,7 @@ func TestKillOnContextDone(t *testing.T) {
 		}
 	}, coro.KillOnContextDone(ctx))
 
-	alive := resume()
+	alive := co.Resume()
 
 	select {
 	case p := <-panicked:
@@ -132,8 +260,188 @@ func TestKillOnContextDone(t *testing.T) {
 		t.Fatalf("expected context cancel to cause a panic")
 	}
 
-	alive = resume()
+	alive = co.Resume()
 	if alive {
 		t.Fatalf("coroutine reported as alive on context cancel")
 	}
+
+	if got := co.Status(); got != coro.StatusDead {
+		t.Fatalf("expected coroutine to be dead, got %v", got)
+	}
+	if err := co.Cause(); !errors.As(err, &coro.ErrKilled{}) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Cause to report context.Canceled within an ErrKilled, got %v", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	var co *coro.Coroutine
+	co = coro.NewCoroutine(func(yield func()) {
+		if got := co.Status(); got != coro.StatusRunning {
+			t.Errorf("expected coroutine to report itself as running, got %v", got)
+		}
+		yield()
+	})
+
+	if got := co.Status(); got != coro.StatusSuspended {
+		t.Fatalf("expected new coroutine to be suspended, got %v", got)
+	}
+
+	if !co.Resume() {
+		t.Fatalf("expected coroutine to still be alive after first yield")
+	}
+	if got := co.Status(); got != coro.StatusSuspended {
+		t.Fatalf("expected yielded coroutine to be suspended, got %v", got)
+	}
+
+	if co.Resume() {
+		t.Fatalf("expected coroutine to be dead after returning")
+	}
+	if got := co.Status(); got != coro.StatusDead {
+		t.Fatalf("expected returned coroutine to be dead, got %v", got)
+	}
+	if err := co.Cause(); err != nil {
+		t.Fatalf("expected a normal return to have a nil Cause, got %v", err)
+	}
+}
+
+func TestKill(t *testing.T) {
+	cause := errors.New("shutting down")
+
+	co := coro.NewCoroutine(func(yield func()) {
+		for {
+			yield()
+		}
+	})
+
+	co.Resume()
+
+	if err := co.Kill(cause); err != nil {
+		t.Fatalf("expected Kill to return nil, got %v", err)
+	}
+
+	if co.Resume() {
+		t.Fatalf("expected killed coroutine to report as dead")
+	}
+	if err := co.Cause(); !errors.As(err, &coro.ErrKilled{}) || !errors.Is(err, cause) {
+		t.Fatalf("expected Cause to report the kill cause within an ErrKilled, got %v", err)
+	}
+}
+
+func ExampleNewSymmetric() {
+	var ping, pong *coro.Coroutine
+
+	ping = coro.NewSymmetric(ctx, func(self *coro.Coroutine, transfer func(*coro.Coroutine)) {
+		for i := 1; i <= 3; i++ {
+			fmt.Println("ping:", i)
+			transfer(pong)
+		}
+	})
+	pong = coro.NewSymmetric(ctx, func(self *coro.Coroutine, transfer func(*coro.Coroutine)) {
+		for {
+			fmt.Println("pong")
+			transfer(ping)
+		}
+	})
+
+	ping.Resume()
+
+	// Output:
+	// ping: 1
+	// pong
+	// ping: 2
+	// pong
+	// ping: 3
+	// pong
+}
+
+// TestTransferToDeadCoroutine checks that transferring to an already-dead
+// Coroutine panics with ErrKilled on the transferring coroutine, same as
+// Kill does, rather than deadlocking waiting for a wake that'll never come.
+func TestTransferToDeadCoroutine(t *testing.T) {
+	dead := coro.NewSymmetric(ctx, func(self *coro.Coroutine, transfer func(*coro.Coroutine)) {})
+	dead.Resume()
+	if got := dead.Status(); got != coro.StatusDead {
+		t.Fatalf("expected coroutine to be dead, got %v", got)
+	}
+
+	co := coro.NewSymmetric(ctx, func(self *coro.Coroutine, transfer func(*coro.Coroutine)) {
+		transfer(dead)
+	})
+
+	if co.Resume() {
+		t.Fatalf("expected coroutine to be dead after transferring to a dead peer")
+	}
+	if err := co.Cause(); !errors.As(err, &coro.ErrKilled{}) {
+		t.Fatalf("expected Cause to report an ErrKilled, got %v", err)
+	}
+}
+
+// TestTransferToConcurrentlyKilledCoroutine checks that transferring to a
+// target that dies concurrently, in the window between transfer checking
+// Status and sending the wake, still panics with ErrKilled rather than
+// blocking forever: since nothing is left reading the target's wake once
+// it's dead, the race is only caught through target.state.done.
+//
+// target is given a head start to reach its initial wait before it's
+// killed, so the kill has nobody to race against except its own unwinding:
+// the race under test is purely how long target's goroutine takes to notice
+// the kill and report itself dead, which is the same TOCTOU window transfer
+// has to deal with in general (Status() can always be stale by the time the
+// subsequent select runs). The race window is narrow, so this repeats the
+// transfer many times to make it likely to land within it at least once,
+// same as how the race was originally found.
+func TestTransferToConcurrentlyKilledCoroutine(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		target := coro.NewSymmetric(ctx, func(self *coro.Coroutine, transfer func(*coro.Coroutine)) {})
+		time.Sleep(time.Millisecond)
+		target.Kill(errors.New("killed concurrently"))
+
+		source := coro.NewSymmetric(ctx, func(self *coro.Coroutine, transfer func(*coro.Coroutine)) {
+			transfer(target)
+		})
+
+		resumed := make(chan struct{})
+		go func() {
+			source.Resume()
+			close(resumed)
+		}()
+
+		select {
+		case <-resumed:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("iteration %d: Resume hung transferring to a concurrently killed coroutine", i)
+		}
+
+		if err := source.Cause(); !errors.As(err, &coro.ErrKilled{}) {
+			t.Fatalf("iteration %d: expected Cause to report an ErrKilled, got %v", i, err)
+		}
+	}
+}
+
+func TestPanicPropagation(t *testing.T) {
+	co := coro.NewCoroutine(func(yield func()) {
+		panic("boom")
+	}, coro.WithPanicPropagation())
+
+	defer func() {
+		r := recover()
+		cp, ok := r.(coro.CoroutinePanic)
+		if !ok {
+			t.Fatalf("expected Resume to re-panic with a coro.CoroutinePanic, got %v", r)
+		}
+		if cp.Value != "boom" {
+			t.Fatalf("expected the panic value to be preserved, got %v", cp.Value)
+		}
+		if len(cp.Stack) == 0 {
+			t.Fatalf("expected the panic to carry a captured stack trace")
+		}
+		if got := co.Status(); got != coro.StatusDead {
+			t.Fatalf("expected coroutine to be dead, got %v", got)
+		}
+		if _, ok := co.Cause().(coro.CoroutinePanic); !ok {
+			t.Fatalf("expected Cause to report the CoroutinePanic, got %v", co.Cause())
+		}
+	}()
+
+	co.Resume()
 }
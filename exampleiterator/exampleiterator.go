@@ -2,7 +2,9 @@
 package exampleiterator
 
 import (
-	"github.com/tcard/coro"
+	"context"
+
+	"github.com/tcard/coro/v2"
 )
 
 // Foo is the type that a FooIterator yields.
@@ -11,19 +13,39 @@ type Foo string
 // NewFooIterator wraps coro.NewIterator with a type-safe interface.
 func NewFooIterator(f func(yield func(Foo)) error, options ...coro.SetOption) *FooIterator {
 	var it FooIterator
-	it.Next = coro.New(func(yield func()) {
+	co := coro.New(context.Background(), func(yield func()) {
 		it.Returned = f(func(v Foo) {
 			it.Yielded = v
 			yield()
 		})
-	}, options...)
+	}, append(options, coro.WithPanicPropagation())...)
+	it.Next = func() (alive bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				cp, ok := r.(coro.CoroutinePanic)
+				if !ok {
+					panic(r)
+				}
+				it.Returned = cp
+				alive = false
+			}
+		}()
+		alive = co.Resume()
+		if !alive && it.Returned == nil {
+			it.Returned = co.Cause()
+		}
+		return alive
+	}
 	return &it
 }
 
 // A FooIterator holds what's needed to iterate Foos.
 type FooIterator struct {
 	// Next blocks until the next Foo is set on Yielded, or until the iterator
-	// coroutine returns with a (maybe nil) error, which is set on Returned.
+	// coroutine returns with a (maybe nil) error, which is set on Returned. A
+	// panic from the generator function surfaces here too: it's recovered
+	// and set on Returned as a coro.CoroutinePanic, rather than propagating
+	// out of Next.
 	Next     coro.Resume
 	Yielded  Foo
 	Returned error
@@ -3,6 +3,9 @@ package exampleiterator
 import (
 	"errors"
 	"fmt"
+	"testing"
+
+	"github.com/tcard/coro/v2"
 )
 
 func Example() {
@@ -24,3 +27,28 @@ func Example() {
 	// yielded: baz
 	// returned: done
 }
+
+func TestNewFooIteratorPanic(t *testing.T) {
+	it := NewFooIterator(func(yield func(Foo)) error {
+		yield("foo")
+		panic("boom")
+	})
+
+	if !it.Next() {
+		t.Fatalf("expected first Next to yield a value")
+	}
+	if it.Yielded != "foo" {
+		t.Fatalf("expected to yield %q, got %q", "foo", it.Yielded)
+	}
+
+	if it.Next() {
+		t.Fatalf("expected second Next to report the generator is done")
+	}
+	cp, ok := it.Returned.(coro.CoroutinePanic)
+	if !ok {
+		t.Fatalf("expected Returned to hold a coro.CoroutinePanic, got %v", it.Returned)
+	}
+	if cp.Value != "boom" {
+		t.Fatalf("expected the panic value to be preserved, got %v", cp.Value)
+	}
+}
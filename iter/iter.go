@@ -0,0 +1,129 @@
+//go:build go1.23
+
+// Package iter bridges coro coroutines to the standard library's iter.Seq
+// and iter.Seq2, so coro generators can be consumed with a plain
+// range-over-func loop, and a plain iter.Seq/iter.Seq2 can be driven
+// through coro's pull-based Resume protocol.
+package iter
+
+import (
+	"context"
+	"errors"
+	stditer "iter"
+	"sync"
+
+	"github.com/tcard/coro/v2"
+)
+
+// ErrStopped is the cause a Seq or Seq2 coroutine is killed with when the
+// range loop consuming it stops before the generator returns.
+var ErrStopped = errors.New("coro/iter: range stopped early")
+
+// A Generator reports the error returned by the generator function passed
+// to Seq or Seq2, once the sequence it produced has been fully consumed.
+// It exists because iter.Seq and iter.Seq2 have no channel of their own for
+// a final error.
+type Generator struct {
+	mu  sync.Mutex
+	err error
+}
+
+// Err returns the generator function's returned error, or nil if the
+// sequence hasn't been consumed to completion yet.
+func (g *Generator) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
+
+func (g *Generator) setErr(err error) {
+	g.mu.Lock()
+	g.err = err
+	g.mu.Unlock()
+}
+
+// Seq adapts a coro generator function into an iter.Seq, suitable for
+// ranging over with a standard for ... range loop.
+//
+// Each range over the returned iter.Seq runs f in a fresh coroutine. If the
+// range loop's body stops iterating before f returns (break, return, or a
+// panic), the coroutine is killed rather than leaked, same as any other
+// coro coroutine.
+//
+// Since iter.Seq has no room for a final error, f's returned error is
+// available from the returned Generator's Err method once the sequence has
+// been consumed to completion.
+func Seq[V any](ctx context.Context, f func(yield func(V)) error, setOption ...coro.SetOption) (stditer.Seq[V], *Generator) {
+	g := &Generator{}
+	return func(yield func(V) bool) {
+		var v V
+		co := coro.New(ctx, func(coroYield func()) {
+			g.setErr(f(func(out V) {
+				v = out
+				coroYield()
+			}))
+		}, setOption...)
+		for co.Resume() {
+			if !yield(v) {
+				co.Kill(ErrStopped)
+				return
+			}
+		}
+	}, g
+}
+
+// Seq2 is like Seq, except the generator yields key/value pairs, matching
+// iter.Seq2.
+func Seq2[K, V any](ctx context.Context, f func(yield func(K, V)) error, setOption ...coro.SetOption) (stditer.Seq2[K, V], *Generator) {
+	g := &Generator{}
+	return func(yield func(K, V) bool) {
+		var k K
+		var v V
+		co := coro.New(ctx, func(coroYield func()) {
+			g.setErr(f(func(outK K, outV V) {
+				k, v = outK, outV
+				coroYield()
+			}))
+		}, setOption...)
+		for co.Resume() {
+			if !yield(k, v) {
+				co.Kill(ErrStopped)
+				return
+			}
+		}
+	}, g
+}
+
+// FromSeq turns a pull-based iter.Seq into a coro.Resume-driven iterator:
+// each call to the returned next function pulls one more value from seq,
+// setting it on the variable pointed to by its argument, until seq is
+// exhausted.
+//
+// If next stops being called before seq is exhausted, the coroutine driving
+// seq is killed rather than leaked, through the usual ErrKilled/leak path.
+func FromSeq[V any](ctx context.Context, seq stditer.Seq[V], setOption ...coro.SetOption) (next func(*V) (alive bool)) {
+	return coro.Enumerate(ctx, func(yield func(V)) {
+		seq(func(v V) bool {
+			yield(v)
+			return true
+		})
+	}, setOption...)
+}
+
+// FromSeq2 is like FromSeq, except it adapts a pull-based iter.Seq2, and
+// each call to next sets a key and a value.
+func FromSeq2[K, V any](ctx context.Context, seq stditer.Seq2[K, V], setOption ...coro.SetOption) (next func(*K, *V) (alive bool)) {
+	var kp *K
+	var vp *V
+	co := coro.New(ctx, func(yield func()) {
+		seq(func(k K, v V) bool {
+			*kp, *vp = k, v
+			yield()
+			return true
+		})
+	}, setOption...)
+	return func(k *K, v *V) bool {
+		kp, vp = k, v
+		return co.Resume()
+	}
+}
@@ -0,0 +1,159 @@
+//go:build go1.23
+
+package iter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tcard/coro/v2"
+	"github.com/tcard/coro/v2/iter"
+)
+
+var ctx = context.Background()
+
+func ExampleSeq() {
+	seq, gen := iter.Seq(ctx, func(yield func(int)) error {
+		for i := 1; i <= 3; i++ {
+			yield(i)
+		}
+		return errors.New("done")
+	})
+
+	for v := range seq {
+		fmt.Println("got:", v)
+	}
+	fmt.Println("err:", gen.Err())
+
+	// Output:
+	// got: 1
+	// got: 2
+	// got: 3
+	// err: done
+}
+
+func ExampleSeq_stopEarly() {
+	seq, _ := iter.Seq(ctx, func(yield func(int)) error {
+		for i := 1; i <= 3; i++ {
+			yield(i)
+		}
+		return nil
+	})
+
+	for v := range seq {
+		fmt.Println("got:", v)
+		if v == 2 {
+			break
+		}
+	}
+
+	// Output:
+	// got: 1
+	// got: 2
+}
+
+func ExampleFromSeq() {
+	seq, _ := iter.Seq(ctx, func(yield func(int)) error {
+		for i := 1; i <= 3; i++ {
+			yield(i)
+		}
+		return nil
+	})
+
+	var v int
+	next := iter.FromSeq(ctx, seq)
+	for next(&v) {
+		fmt.Println("got:", v)
+	}
+
+	// Output:
+	// got: 1
+	// got: 2
+	// got: 3
+}
+
+func ExampleSeq2() {
+	seq, gen := iter.Seq2(ctx, func(yield func(int, string)) error {
+		for i, s := range []string{"foo", "bar", "baz"} {
+			yield(i, s)
+		}
+		return errors.New("done")
+	})
+
+	for i, s := range seq {
+		fmt.Println("got:", i, s)
+	}
+	fmt.Println("err:", gen.Err())
+
+	// Output:
+	// got: 0 foo
+	// got: 1 bar
+	// got: 2 baz
+	// err: done
+}
+
+func ExampleFromSeq2() {
+	seq, _ := iter.Seq2(ctx, func(yield func(int, string)) error {
+		for i, s := range []string{"foo", "bar", "baz"} {
+			yield(i, s)
+		}
+		return nil
+	})
+
+	var i int
+	var s string
+	next := iter.FromSeq2(ctx, seq)
+	for next(&i, &s) {
+		fmt.Println("got:", i, s)
+	}
+
+	// Output:
+	// got: 0 foo
+	// got: 1 bar
+	// got: 2 baz
+}
+
+// TestSeq_stopEarlyKillsCoroutine pins down the guarantee ExampleSeq_stopEarly
+// can only demonstrate from the outside: breaking out of the range loop
+// doesn't just stop pulling values, it kills the underlying coroutine with
+// iter.ErrStopped, same as any other coro coroutine abandoned mid-flight.
+func TestSeq_stopEarlyKillsCoroutine(t *testing.T) {
+	result := make(chan error, 1)
+	seq, _ := iter.Seq(ctx, func(yield func(int)) error {
+		defer func() {
+			r := recover()
+			ek, ok := r.(coro.ErrKilled)
+			switch {
+			case !ok:
+				result <- fmt.Errorf("expected a panic wrapping coro.ErrKilled, got %v", r)
+			case !errors.Is(ek, iter.ErrStopped):
+				result <- fmt.Errorf("expected the ErrKilled to wrap iter.ErrStopped, got %v", ek)
+			default:
+				result <- nil
+			}
+			panic(r)
+		}()
+		for i := 1; i <= 3; i++ {
+			yield(i)
+		}
+		return nil
+	})
+
+	for v := range seq {
+		if v == 2 {
+			break
+		}
+	}
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the range-stopped coroutine to be killed")
+	}
+}